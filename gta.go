@@ -1,15 +1,26 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"go/build"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/Masterminds/glide/dependency"
 	gpath "github.com/Masterminds/glide/path"
+	"github.com/olekukonko/gta/internal/constraint"
+	"github.com/olekukonko/gta/internal/importers"
+	"github.com/olekukonko/gta/internal/report"
+	"github.com/olekukonko/gta/internal/verify"
 	"github.com/sdboyer/gps"
 	"github.com/spf13/cobra"
 )
@@ -26,31 +37,45 @@ of that repository exist, then gta can be used to determine if your build will
 
 $ gta github.com/foo/bar
 
+The dependency argument may be suffixed with @<constraint> to restrict the
+sweep to a semver range, a branch, a tag, or a specific 40-character
+revision, e.g. ` + "`gta github.com/foo/bar@^1.2.3`" + ` or ` + "`gta github.com/foo/bar@some-branch`" + `.
+
 By default, gta will simply determine if a dependency solution exists that's
 viable for each dep version. However, if a value is passed for --run, then
 gta will also execute that command for each solution. ` + "`go test`" + ` is usually
 the simplest useful command to run here.
 
 Unless --no-pm is specified, gta will try to detect if metadata files for
-package managers (currently only glide) are present. If so, rather than testing
-all possible versions of the dependency, it will only check versions that are
-allowed by the constraints specified in those files.`,
+package managers (glide, godep, govendor, or dep) are present. If so, rather
+than testing all possible versions of the dependency, it will only check
+versions that are allowed by the constraints specified in those files.
+
+Results are printed as plain text by default; pass --format=json or
+--format=junit to get machine-readable output suitable for CI.
+
+gta remembers which (version, inputs) combinations already passed in
+$XDG_CACHE_HOME/gta/verified.json, and skips re-verifying them on later runs.
+Pass --force to ignore that cache and recheck everything.`,
 	RunE: RunGTA,
 }
 
 var (
-	run                     string
-	branch, semver, version string
+	run    string
+	noPM   bool
+	jobs   int
+	format string
+	force  bool
 )
 
 func main() {
 	// 1. write basic command, absent manifest/lock loading
 	// 2. write support for executing e.g. go test
-	// 3. loader for glide files
 	RootCmd.Flags().StringVarP(&run, "run", "r", "", "Additional command to run (e.g. `go test`) as a check")
-	RootCmd.Flags().StringVarP(&semver, "semver", "v", "", "Semantic version (range or single version) to check")
-	RootCmd.Flags().StringVar(&branch, "branch", "", "Branch to check")
-	RootCmd.Flags().StringVar(&version, "version", "", "Version (non-semver tag) to check")
+	RootCmd.Flags().BoolVar(&noPM, "no-pm", false, "Don't honor constraints from glide/godep/govendor/dep manifests, if present")
+	RootCmd.Flags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "Number of versions to solve/check concurrently")
+	RootCmd.Flags().StringVar(&format, "format", "text", "Result output format: text, json, or junit")
+	RootCmd.Flags().BoolVar(&force, "force", false, "Re-verify versions even if a previous run already recorded them as passing")
 
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -65,9 +90,11 @@ func RunGTA(cmd *cobra.Command, args []string) error {
 		pkg = args[0]
 		break
 	default:
-		return fmt.Errorf("You must specify a single dependency to check against its versions.\n")
+		return fmt.Errorf("You must specify a single dependency (optionally @<constraint>) to check against its versions.\n")
 	}
 
+	pkg, constraintArg := constraint.Parse(pkg)
+
 	wd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("Could not get working directory: %s", err)
@@ -94,29 +121,9 @@ func RunGTA(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("No versions could be located for %s", root)
 	}
 
-	// obnoxious constraint parsing
-	var c gps.Constraint
-	switch {
-	case branch == "" && semver == "" && version == "":
-		c = gps.Any()
-	case branch != "":
-		if semver != "" || version != "" {
-			return fmt.Errorf("Please specify only one type of constraint - branch, version, or semver")
-		}
-		c = gps.NewBranch(branch)
-	case version != "":
-		if semver != "" || branch != "" {
-			return fmt.Errorf("Please specify only one type of constraint - branch, version, or semver")
-		}
-		c = gps.NewVersion(version)
-	case semver != "":
-		if version != "" || branch != "" {
-			return fmt.Errorf("Please specify only one type of constraint - branch, version, or semver")
-		}
-		c, err = gps.NewSemverConstraint(semver)
-		if err != nil {
-			return fmt.Errorf("%s is not a valid semver constraint", semver)
-		}
+	c, err := constraint.Infer(constraintArg, root, sm)
+	if err != nil {
+		return fmt.Errorf("%s is not a valid constraint for %s: %s", constraintArg, root, err)
 	}
 
 	// Assume the current directory is correctly placed on a GOPATH, and derive
@@ -130,7 +137,24 @@ func RunGTA(cmd *cobra.Command, args []string) error {
 		ImportRoot: gps.ProjectRoot(importroot),
 	}
 
-	var vl []Version
+	if !noPM {
+		if im := importers.Detect(wd); im != nil {
+			man, lock, err := im.Import(wd, sm)
+			if err != nil {
+				return fmt.Errorf("Could not import %s metadata: %s", im.Name(), err)
+			}
+			fmt.Printf("Found %s metadata, constraining sweep to its declared versions\n", im.Name())
+			params.Manifest = man
+			params.Lock = lock
+			// Each job pins root to its own version, so the manifest's
+			// constraint on root only takes effect by narrowing the sweep.
+			if pp, ok := man.DependencyConstraints()[root]; ok && pp.Constraint != nil {
+				c = c.Intersect(pp.Constraint)
+			}
+		}
+	}
+
+	var vl []gps.Version
 	for _, v := range vlist {
 		if c.Matches(v) {
 			vl = append(vl, v)
@@ -141,71 +165,231 @@ func RunGTA(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s has %v versions, but none matched constraint %s", root, len(vlist), c)
 	}
 
-	fmt.Println("Checking %s with the following versions: %s", root, vl)
+	fmt.Printf("Checking %s with the following versions: %v\n", root, vl)
 
-	type solnOrErr struct {
-		v   gps.Version
-		s   gps.Solution
-		err error
+	if jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", jobs)
 	}
 
-	solns := make([]solnOrErr, len(vlist))
-	for k, v := range vlist {
-		// TODO assign v into manifest
-		// TODO parallel, bwahaha
-		soe := solnOrErr{v: v}
-		// TODO reparse root project every time...horribly wasteful
-		s, soe.err = gps.Prepare(params, sm)
-		if soe.err == nil {
-			soe.s, soe.err = s.Solve()
-			continue
-		}
+	projectHash, err := verify.HashTree(wd)
+	if err != nil {
+		return fmt.Errorf("Could not hash project tree: %s", err)
+	}
+	inputsHash := verify.HashStrings(fmt.Sprintf("%#v", params.Manifest), fmt.Sprintf("%#v", params.Lock), c.String(), run)
 
-		solns[k] = soe
+	cache, err := verify.Open(verify.DefaultPath())
+	if err != nil {
+		return fmt.Errorf("Could not open verify cache: %s", err)
 	}
+	var cacheMu sync.Mutex
 
-	// If we have to create these vendor trees, then back up the original vendor
-	vpath := filepath.Join(root, "vendor")
-	if run != "" {
-		if _, err = os.Stat(); err != nil {
-			err = os.Rename(vpath, filepath.Join(root, "_origvendor"))
-			if err != nil {
-				return fmt.Errorf("Failed to back up vendor folder: %s", err)
+	jobsCh := make(chan gps.Version)
+	resultsCh := make(chan report.Attempt)
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range jobsCh {
+				a := report.Attempt{Version: v.String()}
+				key := verify.Key(projectHash, fmt.Sprintf("%s@%s", root, v), inputsHash)
+
+				// Each job gets its own solver, prepped against a manifest
+				// that pins root to this job's version, so the solve is
+				// actually specific to the version under test. gps.Solver
+				// is stateful and not safe to share or call concurrently.
+				jobParams := params
+				jobParams.Manifest = manifestForVersion(params.Manifest, root, v)
+
+				start := time.Now()
+				solver, solveErr := gps.Prepare(jobParams, sm)
+				var soln gps.Solution
+				if solveErr == nil {
+					soln, solveErr = solver.Solve()
+				}
+				a.SolveDuration = time.Since(start)
+				if solveErr != nil {
+					a.SolveError = solveErr.Error()
+					resultsCh <- a
+					continue
+				}
+
+				// Solving is cheap next to actually writing a vendor tree
+				// and running the check command, so it always happens -
+				// that's what lets us detect a cache entry gone stale
+				// because a branch or tag moved upstream since it was
+				// recorded, even though the cache key itself is unchanged.
+				vendorHash := verify.HashStrings(fmt.Sprintf("%#v", soln))
+
+				cacheMu.Lock()
+				alreadyPassed := !force && cache.Passed(key, vendorHash)
+				cacheMu.Unlock()
+				if alreadyPassed {
+					a.Cached = true
+					resultsCh <- a
+					continue
+				}
+
+				if run != "" {
+					a.Command = run
+					start = time.Now()
+					var infraErr error
+					a.Stdout, a.Stderr, a.ExitCode, infraErr = runInIsolatedTree(wd, run, v, soln, sm)
+					a.RunDuration = time.Since(start)
+					if infraErr != nil {
+						a.InfraError = infraErr.Error()
+					}
+				}
+
+				if a.Passed() {
+					cacheMu.Lock()
+					cache.Record(key, verify.Entry{Passed: true, VendorHash: vendorHash})
+					cacheMu.Unlock()
+				}
+
+				resultsCh <- a
 			}
-			defer os.Rename(filepath.Join(root, "_origvendor"), vpath)
-		}
+		}()
 	}
 
+	go func() {
+		for _, v := range vl {
+			jobsCh <- v
+		}
+		close(jobsCh)
+		wg.Wait()
+		close(resultsCh)
+	}()
+
+	var attempts []report.Attempt
 	var fail bool
-	for k, soln := range solns {
-		nv := fmt.Sprintf("%s@%s", root, soln.v)
-		if soln.err != nil {
+	for a := range resultsCh {
+		if !a.Passed() {
 			fail = true
-			fmt.Printf("%s failed solving: %s\n", nv, soln.err)
-			continue
 		}
+		attempts = append(attempts, a)
+	}
 
-		if run != "" {
-			fmt.Printf("%s succeeded", nv)
-		} else {
-			err = gps.WriteSourceTree(vpath, soln.s, sm)
-			if err != nil {
-				fail = true
-				fmt.Printf("could not write tree for %s, skipping check", nv)
-				continue
-			}
+	rep, err := report.New(format, os.Stdout)
+	if err != nil {
+		return err
+	}
+	if err := rep.Report(string(root), attempts); err != nil {
+		return fmt.Errorf("could not render report: %s", err)
+	}
 
-			parts := strings.Split(run, " ")
-			cmd := exec.Command(parts[0], parts[1:]...)
-			out, err := cmd.CombinedOutput()
-			if err != nil {
-				fail = true
-				fmt.Printf("%s failed with %s, output:\n%s", err, string(out))
-			} else {
-				fmt.Printf("%s succeeded", nv)
-			}
+	if err := cache.Save(); err != nil {
+		return fmt.Errorf("Could not save verify cache: %s", err)
+	}
+
+	if fail {
+		return fmt.Errorf("one or more versions of %s failed to solve or pass checks", root)
+	}
+	return nil
+}
 
-			os.RemoveAll(vpath)
+// manifestForVersion returns a manifest matching base, except root's
+// constraint is pinned to v, so a solve against it actually tests that
+// specific version instead of whatever broader constraint root was given.
+func manifestForVersion(base gps.Manifest, root gps.ProjectRoot, v gps.Version) gps.Manifest {
+	deps := make(map[gps.ProjectRoot]gps.ProjectProperties)
+	if base != nil {
+		for pr, pp := range base.DependencyConstraints() {
+			deps[pr] = pp
 		}
 	}
+	deps[root] = gps.ProjectProperties{Constraint: v}
+	return gps.SimpleManifest{Deps: deps}
+}
+
+// runInIsolatedTree solves version v's solution into its own vendor tree
+// inside a scratch copy of root, then runs the check command there. Each
+// call gets a private copy so concurrent workers never stomp on each
+// other's vendor directory.
+//
+// infraErr is set when gta itself failed to stage the check - a scratch dir,
+// checkout copy, or vendor tree write failing - as distinct from the command
+// under test running and exiting non-zero. Callers must not attribute
+// infraErr to the dependency version or its solve.
+func runInIsolatedTree(rootDir, run string, v gps.Version, soln gps.Solution, sm gps.SourceManager) (stdout, stderr string, exitCode int, infraErr error) {
+	scratch, err := ioutil.TempDir("", "gta-")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("could not create scratch dir for %s: %s", v, err)
+	}
+	defer os.RemoveAll(scratch)
+
+	if err := copyTree(rootDir, scratch); err != nil {
+		return "", "", 0, fmt.Errorf("could not stage checkout copy for %s: %s", v, err)
+	}
+
+	vpath := filepath.Join(scratch, "vendor")
+	if err := gps.WriteSourceTree(vpath, soln, sm); err != nil {
+		return "", "", 0, fmt.Errorf("could not write vendor tree for %s: %s", v, err)
+	}
+
+	parts := strings.Split(run, " ")
+	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Dir = scratch
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=vendor")
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	runErr := cmd.Run()
+	code := 0
+	if exitErr, ok := runErr.(*exec.ExitError); ok {
+		code = exitErr.Sys().(syscall.WaitStatus).ExitStatus()
+		runErr = nil
+	} else if runErr != nil {
+		return "", "", 0, fmt.Errorf("could not run %q for %s: %s", run, v, runErr)
+	}
+
+	return outBuf.String(), errBuf.String(), code, nil
+}
+
+// copyTree copies src into dst, skipping vendor/ and .git, which the caller
+// is responsible for populating (or which have no business in a scratch
+// checkout) itself.
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == "vendor" || rel == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode())
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		_, err = io.Copy(out, in)
+		return err
+	})
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sdboyer/gps"
+)
+
+func TestManifestForVersionPinsRoot(t *testing.T) {
+	root := gps.ProjectRoot("github.com/foo/bar")
+	v := gps.NewVersion("v1.2.3")
+
+	man := manifestForVersion(nil, root, v)
+
+	deps := man.DependencyConstraints()
+	pp, ok := deps[root]
+	if !ok {
+		t.Fatalf("expected a constraint for %s, got %v", root, deps)
+	}
+	if pp.Constraint != v {
+		t.Errorf("got constraint %v, want root pinned to %v", pp.Constraint, v)
+	}
+}
+
+func TestManifestForVersionPreservesOtherConstraints(t *testing.T) {
+	root := gps.ProjectRoot("github.com/foo/bar")
+	other := gps.ProjectRoot("github.com/baz/quux")
+	base := gps.SimpleManifest{
+		Deps: map[gps.ProjectRoot]gps.ProjectProperties{
+			other: {Constraint: gps.NewBranch("master")},
+		},
+	}
+
+	man := manifestForVersion(base, root, gps.NewVersion("v1.2.3"))
+
+	deps := man.DependencyConstraints()
+	if _, ok := deps[root]; !ok {
+		t.Fatalf("expected a constraint for %s, got %v", root, deps)
+	}
+	pp, ok := deps[other]
+	if !ok {
+		t.Fatalf("expected base's constraint for %s to survive, got %v", other, deps)
+	}
+	if pp.Constraint.String() != "master" {
+		t.Errorf("got constraint %v for %s, want master preserved from base", pp.Constraint, other)
+	}
+}
+
+func TestManifestForVersionDiffersPerVersion(t *testing.T) {
+	root := gps.ProjectRoot("github.com/foo/bar")
+
+	manA := manifestForVersion(nil, root, gps.NewVersion("v1.0.0"))
+	manB := manifestForVersion(nil, root, gps.NewVersion("v2.0.0"))
+
+	ppA := manA.DependencyConstraints()[root]
+	ppB := manB.DependencyConstraints()[root]
+
+	if ppA.Constraint == ppB.Constraint {
+		t.Fatalf("expected distinct pinned versions, both got %v", ppA.Constraint)
+	}
+}
@@ -0,0 +1,67 @@
+// Package constraint turns the dep-style `<import path>[@<constraint>]`
+// argument syntax into a gps.Constraint, so callers don't have to juggle
+// mutually-exclusive --branch/--semver/--version flags.
+package constraint
+
+import (
+	"regexp"
+
+	"github.com/sdboyer/gps"
+)
+
+var revisionRE = regexp.MustCompile(`^[a-fA-F0-9]{40}$`)
+
+// Parse splits a <import path>[@<constraint>] argument into its import path
+// and constraint string. If no "@" is present, the constraint string is
+// empty, meaning "no constraint specified".
+func Parse(arg string) (path, constraint string) {
+	for i := 0; i < len(arg); i++ {
+		if arg[i] == '@' {
+			return arg[:i], arg[i+1:]
+		}
+	}
+	return arg, ""
+}
+
+// Infer derives a gps.Constraint from s for the given project root. This
+// mirrors gps's own SourceManager.InferConstraint logic:
+//
+//  1. An empty s means "no constraint" - gps.Any() is returned.
+//  2. s is tried as a semver constraint/range first.
+//  3. A 40-character hex string is treated as a revision.
+//  4. Otherwise, s is matched against the project's known branches and
+//     tags; a match returns a constraint pinned to that exact version.
+//     Failing that, s is treated as a plain (non-semver) tag.
+func Infer(s string, root gps.ProjectRoot, sm gps.SourceManager) (gps.Constraint, error) {
+	if s == "" {
+		return gps.Any(), nil
+	}
+
+	if c, err := gps.NewSemverConstraintIC(s); err == nil {
+		return c, nil
+	}
+
+	if revisionRE.MatchString(s) {
+		return gps.Revision(s), nil
+	}
+
+	vlist, err := sm.ListVersions(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range vlist {
+		switch tv := v.(type) {
+		case gps.PairedVersion:
+			if tv.String() == s {
+				return tv, nil
+			}
+		case gps.Version:
+			if tv.String() == s {
+				return tv, nil
+			}
+		}
+	}
+
+	return gps.NewVersion(s), nil
+}
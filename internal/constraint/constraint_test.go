@@ -0,0 +1,102 @@
+package constraint
+
+import (
+	"testing"
+
+	"github.com/sdboyer/gps"
+)
+
+// fakeSM implements gps.SourceManager by embedding a nil instance and
+// overriding only ListVersions, which is all Infer's branch/tag fallback
+// path needs. Any other method would panic if called.
+type fakeSM struct {
+	gps.SourceManager
+	versions []gps.Version
+}
+
+func (f fakeSM) ListVersions(gps.ProjectRoot) ([]gps.Version, error) {
+	return f.versions, nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name           string
+		arg            string
+		wantPath       string
+		wantConstraint string
+	}{
+		{"no constraint", "github.com/foo/bar", "github.com/foo/bar", ""},
+		{"semver constraint", "github.com/foo/bar@^1.2.3", "github.com/foo/bar", "^1.2.3"},
+		{"branch constraint", "github.com/foo/bar@some-branch", "github.com/foo/bar", "some-branch"},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, c := Parse(tt.arg)
+			if path != tt.wantPath || c != tt.wantConstraint {
+				t.Errorf("Parse(%q) = (%q, %q), want (%q, %q)", tt.arg, path, c, tt.wantPath, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestInfer(t *testing.T) {
+	root := gps.ProjectRoot("github.com/foo/bar")
+
+	t.Run("empty string is unconstrained", func(t *testing.T) {
+		c, err := Infer("", root, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !c.Matches(gps.NewVersion("v9.9.9")) {
+			t.Errorf("expected %v to match anything", c)
+		}
+	})
+
+	t.Run("semver constraint", func(t *testing.T) {
+		c, err := Infer("^1.2.3", root, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !c.Matches(gps.NewVersion("v1.2.4")) {
+			t.Errorf("expected %v to match v1.2.4", c)
+		}
+		if c.Matches(gps.NewVersion("v2.0.0")) {
+			t.Errorf("expected %v not to match v2.0.0", c)
+		}
+	})
+
+	t.Run("40-char hex string is a revision", func(t *testing.T) {
+		rev := "abcdef0123456789abcdef0123456789abcdef01"
+		c, err := Infer(rev, root, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if c != gps.Revision(rev) {
+			t.Errorf("got %v, want revision %s", c, rev)
+		}
+	})
+
+	t.Run("matches a known branch", func(t *testing.T) {
+		sm := fakeSM{versions: []gps.Version{gps.NewBranch("release-1.0")}}
+		c, err := Infer("release-1.0", root, sm)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if c.String() != "release-1.0" {
+			t.Errorf("got %v, want release-1.0", c)
+		}
+	})
+
+	t.Run("falls back to a plain tag", func(t *testing.T) {
+		sm := fakeSM{versions: []gps.Version{gps.NewVersion("v0.1.0")}}
+		c, err := Infer("some-weird-tag", root, sm)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if c.String() != "some-weird-tag" {
+			t.Errorf("got %v, want some-weird-tag", c)
+		}
+	})
+}
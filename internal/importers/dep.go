@@ -0,0 +1,77 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/sdboyer/gps"
+)
+
+// DepImporter reads constraints out of Gopkg.toml and pinned revisions out
+// of Gopkg.lock. Unlike the other importers, these files already speak
+// gps's vocabulary directly, so this is mostly a straight translation.
+type DepImporter struct{}
+
+func (DepImporter) Name() string { return "dep" }
+
+func (DepImporter) HasMetadata(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "Gopkg.toml"))
+	return err == nil
+}
+
+type depManifest struct {
+	Constraint []struct {
+		Name     string
+		Branch   string
+		Version  string
+		Revision string
+	} `toml:"constraint"`
+}
+
+type depLock struct {
+	Projects []struct {
+		Name     string
+		Revision string
+		Version  string
+	} `toml:"projects"`
+}
+
+func (DepImporter) Import(dir string, sm gps.SourceManager) (gps.Manifest, gps.Lock, error) {
+	var dm depManifest
+	if _, err := toml.DecodeFile(filepath.Join(dir, "Gopkg.toml"), &dm); err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(map[gps.ProjectRoot]gps.ProjectProperties, len(dm.Constraint))
+	for _, c := range dm.Constraint {
+		root := gps.ProjectRoot(c.Name)
+		switch {
+		case c.Revision != "":
+			deps[root] = gps.ProjectProperties{Constraint: gps.Revision(c.Revision)}
+		case c.Branch != "":
+			deps[root] = gps.ProjectProperties{Constraint: gps.NewBranch(c.Branch)}
+		default:
+			deps[root] = gps.ProjectProperties{Constraint: gpsConstraint(c.Version)}
+		}
+	}
+	man := gps.SimpleManifest{Deps: deps}
+
+	lockPath := filepath.Join(dir, "Gopkg.lock")
+	if _, err := os.Stat(lockPath); err != nil {
+		return man, nil, nil
+	}
+
+	var dl depLock
+	if _, err := toml.DecodeFile(lockPath, &dl); err != nil {
+		return man, nil, err
+	}
+
+	lp := make([]gps.LockedProject, 0, len(dl.Projects))
+	for _, p := range dl.Projects {
+		id := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot(p.Name)}
+		lp = append(lp, gps.NewLockedProject(id, gps.Revision(p.Revision), nil))
+	}
+
+	return man, gps.SimpleLock(lp), nil
+}
@@ -0,0 +1,71 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Masterminds/glide/cfg"
+	"github.com/sdboyer/gps"
+)
+
+// GlideImporter reads constraints and pinned revisions out of glide.yaml and
+// glide.lock.
+type GlideImporter struct{}
+
+func (GlideImporter) Name() string { return "glide" }
+
+func (GlideImporter) HasMetadata(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "glide.yaml"))
+	return err == nil
+}
+
+func (GlideImporter) Import(dir string, sm gps.SourceManager) (gps.Manifest, gps.Lock, error) {
+	yamlPath := filepath.Join(dir, "glide.yaml")
+	conf, err := cfg.ReadConfigFile(yamlPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(map[gps.ProjectRoot]gps.ProjectProperties, len(conf.Imports))
+	for _, imp := range conf.Imports {
+		root := gps.ProjectRoot(imp.Name)
+		deps[root] = gps.ProjectProperties{
+			Constraint: gpsConstraint(imp.Reference),
+		}
+	}
+	man := gps.SimpleManifest{Deps: deps}
+
+	lockPath := filepath.Join(dir, "glide.lock")
+	if _, err := os.Stat(lockPath); err != nil {
+		// No lock file is fine; the manifest alone still constrains the
+		// sweep.
+		return man, nil, nil
+	}
+
+	lockfile, err := cfg.ReadLockFile(lockPath)
+	if err != nil {
+		return man, nil, err
+	}
+
+	lp := make([]gps.LockedProject, 0, len(lockfile.Imports))
+	for _, imp := range lockfile.Imports {
+		id := gps.ProjectIdentifier{ProjectRoot: gps.ProjectRoot(imp.Name)}
+		lp = append(lp, gps.NewLockedProject(id, gps.Revision(imp.Version), nil))
+	}
+
+	return man, gps.SimpleLock(lp), nil
+}
+
+// gpsConstraint turns a reference into a gps.Constraint: gps.Any() when it is
+// empty, a semver constraint when it parses as one, and otherwise a plain
+// version (tag). Branch names are not told apart from tags, so a branch
+// reference yields a constraint that matches no branch.
+func gpsConstraint(ref string) gps.Constraint {
+	if ref == "" {
+		return gps.Any()
+	}
+	if c, err := gps.NewSemverConstraintIC(ref); err == nil {
+		return c
+	}
+	return gps.NewVersion(ref)
+}
@@ -0,0 +1,54 @@
+package importers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/sdboyer/gps"
+)
+
+// GodepImporter reads pinned revisions out of Godeps/Godeps.json. Godep
+// records no version ranges, only exact revisions, so the resulting
+// manifest simply constrains each dependency to the revision it recorded.
+type GodepImporter struct{}
+
+func (GodepImporter) Name() string { return "godep" }
+
+func (GodepImporter) HasMetadata(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "Godeps", "Godeps.json"))
+	return err == nil
+}
+
+type godepFile struct {
+	Deps []struct {
+		ImportPath string
+		Rev        string
+	}
+}
+
+func (GodepImporter) Import(dir string, sm gps.SourceManager) (gps.Manifest, gps.Lock, error) {
+	f, err := os.Open(filepath.Join(dir, "Godeps", "Godeps.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var gf godepFile
+	if err := json.NewDecoder(f).Decode(&gf); err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(map[gps.ProjectRoot]gps.ProjectProperties, len(gf.Deps))
+	lp := make([]gps.LockedProject, 0, len(gf.Deps))
+	for _, d := range gf.Deps {
+		root, err := sm.DeduceProjectRoot(d.ImportPath)
+		if err != nil {
+			root = gps.ProjectRoot(d.ImportPath)
+		}
+		deps[root] = gps.ProjectProperties{Constraint: gps.Revision(d.Rev)}
+		lp = append(lp, gps.NewLockedProject(gps.ProjectIdentifier{ProjectRoot: root}, gps.Revision(d.Rev), nil))
+	}
+
+	return gps.SimpleManifest{Deps: deps}, gps.SimpleLock(lp), nil
+}
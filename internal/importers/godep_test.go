@@ -0,0 +1,68 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sdboyer/gps"
+)
+
+// fakeSM implements gps.SourceManager by embedding a nil instance and
+// overriding only DeduceProjectRoot, which is all the importers need.
+type fakeSM struct {
+	gps.SourceManager
+}
+
+func (fakeSM) DeduceProjectRoot(ip string) (gps.ProjectRoot, error) {
+	return gps.ProjectRoot(ip), nil
+}
+
+func TestGodepImporterHasMetadata(t *testing.T) {
+	dir := t.TempDir()
+	if (GodepImporter{}).HasMetadata(dir) {
+		t.Fatal("expected no metadata in an empty dir")
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "Godeps"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Godeps", "Godeps.json"), []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !(GodepImporter{}).HasMetadata(dir) {
+		t.Fatal("expected metadata to be detected once Godeps.json exists")
+	}
+}
+
+func TestGodepImporterImport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "Godeps"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	const rev = "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef"
+	body := `{"Deps":[{"ImportPath":"github.com/foo/bar","Rev":"` + rev + `"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "Godeps", "Godeps.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	man, lock, err := (GodepImporter{}).Import(dir, fakeSM{})
+	if err != nil {
+		t.Fatalf("Import failed: %s", err)
+	}
+
+	deps := man.DependencyConstraints()
+	pp, ok := deps["github.com/foo/bar"]
+	if !ok {
+		t.Fatalf("expected a constraint for github.com/foo/bar, got %v", deps)
+	}
+	if pp.Constraint != gps.Revision(rev) {
+		t.Errorf("got constraint %v, want pinned revision %s", pp.Constraint, rev)
+	}
+
+	if lock == nil {
+		t.Fatal("expected a non-nil lock pinning the same revision")
+	}
+}
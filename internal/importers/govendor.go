@@ -0,0 +1,55 @@
+package importers
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/sdboyer/gps"
+)
+
+// GovendorImporter reads pinned revisions out of vendor/vendor.json.
+type GovendorImporter struct{}
+
+func (GovendorImporter) Name() string { return "govendor" }
+
+func (GovendorImporter) HasMetadata(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "vendor", "vendor.json"))
+	return err == nil
+}
+
+type govendorFile struct {
+	Package []struct {
+		Path     string
+		Revision string
+		Version  string
+	}
+}
+
+func (GovendorImporter) Import(dir string, sm gps.SourceManager) (gps.Manifest, gps.Lock, error) {
+	f, err := os.Open(filepath.Join(dir, "vendor", "vendor.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var vf govendorFile
+	if err := json.NewDecoder(f).Decode(&vf); err != nil {
+		return nil, nil, err
+	}
+
+	deps := make(map[gps.ProjectRoot]gps.ProjectProperties, len(vf.Package))
+	lp := make([]gps.LockedProject, 0, len(vf.Package))
+	for _, p := range vf.Package {
+		root, err := sm.DeduceProjectRoot(p.Path)
+		if err != nil {
+			root = gps.ProjectRoot(p.Path)
+		}
+
+		c := gpsConstraint(p.Version)
+		deps[root] = gps.ProjectProperties{Constraint: c}
+		lp = append(lp, gps.NewLockedProject(gps.ProjectIdentifier{ProjectRoot: root}, gps.Revision(p.Revision), nil))
+	}
+
+	return gps.SimpleManifest{Deps: deps}, gps.SimpleLock(lp), nil
+}
@@ -0,0 +1,42 @@
+// Package importers discovers and reads manifest/lock files left behind by
+// other Go package managers, so gta can constrain its version sweep to
+// whatever a project has already declared rather than testing every tag a
+// dependency ever published.
+package importers
+
+import "github.com/sdboyer/gps"
+
+// Importer knows how to recognize and translate one package manager's
+// metadata files into gps's own manifest/lock types.
+type Importer interface {
+	// Name identifies the importer in logs and error messages.
+	Name() string
+	// HasMetadata reports whether this importer's manifest/lock files are
+	// present in dir.
+	HasMetadata(dir string) bool
+	// Import reads the manifest/lock files rooted at dir and translates
+	// them into a gps.Manifest and (optional) gps.Lock. sm is provided so
+	// importers that need to resolve revisions to versions (or vice versa)
+	// can consult the SourceManager.
+	Import(dir string, sm gps.SourceManager) (gps.Manifest, gps.Lock, error)
+}
+
+// Registered is the list of known importers, in priority order. Detect walks
+// this list and returns the first one whose metadata is present.
+var Registered = []Importer{
+	DepImporter{},
+	GlideImporter{},
+	GodepImporter{},
+	GovendorImporter{},
+}
+
+// Detect returns the first registered importer whose metadata files are
+// present in dir, or nil if none match.
+func Detect(dir string) Importer {
+	for _, im := range Registered {
+		if im.HasMetadata(dir) {
+			return im
+		}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// New returns the Reporter for the named format ("text", "json", or
+// "junit"), writing to w.
+func New(format string, w io.Writer) (Reporter, error) {
+	switch format {
+	case "", "text":
+		return TextReporter{W: w}, nil
+	case "json":
+		return JSONReporter{W: w}, nil
+	case "junit":
+		return JUnitReporter{W: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown report format %q", format)
+	}
+}
@@ -0,0 +1,22 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONReporter writes the full set of attempts as a single JSON document.
+type JSONReporter struct {
+	W io.Writer
+}
+
+type jsonDoc struct {
+	Dependency string    `json:"dependency"`
+	Attempts   []Attempt `json:"attempts"`
+}
+
+func (r JSONReporter) Report(dep string, attempts []Attempt) error {
+	enc := json.NewEncoder(r.W)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonDoc{Dependency: dep, Attempts: attempts})
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// JUnitReporter writes attempts as a JUnit XML test suite, one <testcase>
+// per version, named after the dependency being swept.
+type JUnitReporter struct {
+	W io.Writer
+}
+
+type junitSuite struct {
+	XMLName   xml.Name    `xml:"testsuite"`
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	TestCases []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func (r JUnitReporter) Report(dep string, attempts []Attempt) error {
+	suite := junitSuite{
+		Name:  dep,
+		Tests: len(attempts),
+	}
+
+	for _, a := range attempts {
+		tc := junitCase{
+			Name: a.Version,
+			Time: (a.SolveDuration + a.RunDuration).Seconds(),
+		}
+
+		switch {
+		case a.SolveError != "":
+			tc.Failure = &junitFailure{Message: "solve failed", Body: a.SolveError}
+		case a.InfraError != "":
+			tc.Failure = &junitFailure{Message: "gta infrastructure error", Body: a.InfraError}
+		case a.Command != "" && a.ExitCode != 0:
+			tc.Failure = &junitFailure{Message: a.Command, Body: a.Stdout + a.Stderr}
+		}
+
+		if tc.Failure != nil {
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(r.W, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(r.W)
+	enc.Indent("", "  ")
+	return enc.Encode(suite)
+}
@@ -0,0 +1,47 @@
+// Package report models gta's per-version results and renders them in a
+// choice of formats, so CI systems can consume a run's outcome without
+// scraping text output.
+package report
+
+import "time"
+
+// Attempt records the outcome of checking a single dependency version:
+// whether a solution was found, and if a --run command was executed
+// against it, how that command fared.
+type Attempt struct {
+	Version       string
+	SolveDuration time.Duration
+	SolveError    string
+
+	Command     string
+	ExitCode    int
+	Stdout      string
+	Stderr      string
+	RunDuration time.Duration
+
+	// InfraError is set when gta itself failed to even attempt the run -
+	// e.g. it couldn't stage a scratch tree copy or write the vendor
+	// directory - as opposed to the command it ran failing. Solving and
+	// the dependency version under test are not implicated when this is
+	// set; it's gta's own plumbing that broke.
+	InfraError string
+
+	// Cached is set when this attempt was skipped because a previous run
+	// already verified the same (version, solver input) combination.
+	Cached bool
+}
+
+// Passed reports whether the attempt found a solution and, if a command was
+// run, that the command also succeeded.
+func (a Attempt) Passed() bool {
+	if a.SolveError != "" || a.InfraError != "" {
+		return false
+	}
+	return a.Command == "" || a.ExitCode == 0
+}
+
+// Reporter renders a dependency's attempts in some format.
+type Reporter interface {
+	// Report writes the results of sweeping dep across attempts.
+	Report(dep string, attempts []Attempt) error
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTextReporter(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Attempt
+		want string
+	}{
+		{"cached", Attempt{Version: "v1.0.0", Cached: true}, "already verified"},
+		{"solve error", Attempt{Version: "v1.0.0", SolveError: "no solution"}, "failed solving: no solution"},
+		{"infra error", Attempt{Version: "v1.0.0", InfraError: "could not stage checkout"}, "could not be checked: could not stage checkout"},
+		{"solved only", Attempt{Version: "v1.0.0"}, "solved successfully"},
+		{"run succeeded", Attempt{Version: "v1.0.0", Command: "go test", ExitCode: 0}, "succeeded"},
+		{"run failed", Attempt{Version: "v1.0.0", Command: "go test", ExitCode: 1, Stdout: "FAIL"}, "exit status 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (TextReporter{W: &buf}).Report("github.com/foo/bar", []Attempt{tt.a}); err != nil {
+				t.Fatalf("Report returned error: %s", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output %q does not contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	attempts := []Attempt{
+		{Version: "v1.0.0", SolveDuration: time.Second},
+		{Version: "v2.0.0", InfraError: "could not write vendor tree"},
+	}
+	if err := (JSONReporter{W: &buf}).Report("github.com/foo/bar", attempts); err != nil {
+		t.Fatalf("Report returned error: %s", err)
+	}
+
+	var doc jsonDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %s\n%s", err, buf.String())
+	}
+	if doc.Dependency != "github.com/foo/bar" {
+		t.Errorf("got dependency %q, want github.com/foo/bar", doc.Dependency)
+	}
+	if len(doc.Attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(doc.Attempts))
+	}
+	if doc.Attempts[1].InfraError != "could not write vendor tree" {
+		t.Errorf("InfraError did not round-trip: got %v", doc.Attempts[1])
+	}
+}
+
+func TestJUnitReporter(t *testing.T) {
+	var buf bytes.Buffer
+	attempts := []Attempt{
+		{Version: "v1.0.0"},
+		{Version: "v2.0.0", SolveError: "no solution"},
+		{Version: "v3.0.0", InfraError: "could not create scratch dir"},
+		{Version: "v4.0.0", Command: "go test", ExitCode: 1, Stdout: "FAIL"},
+	}
+	if err := (JUnitReporter{W: &buf}).Report("github.com/foo/bar", attempts); err != nil {
+		t.Fatalf("Report returned error: %s", err)
+	}
+
+	var suite junitSuite
+	if err := xml.Unmarshal(buf.Bytes(), &suite); err != nil {
+		t.Fatalf("output is not valid XML: %s\n%s", err, buf.String())
+	}
+	if suite.Tests != 4 {
+		t.Errorf("got Tests=%d, want 4", suite.Tests)
+	}
+	if suite.Failures != 3 {
+		t.Errorf("got Failures=%d, want 3", suite.Failures)
+	}
+
+	byName := map[string]junitCase{}
+	for _, tc := range suite.TestCases {
+		byName[tc.Name] = tc
+	}
+
+	if tc := byName["v2.0.0"]; tc.Failure == nil || tc.Failure.Message != "solve failed" {
+		t.Errorf("v2.0.0 failure = %v, want message \"solve failed\"", tc.Failure)
+	}
+	if tc := byName["v3.0.0"]; tc.Failure == nil || tc.Failure.Message != "gta infrastructure error" {
+		t.Errorf("v3.0.0 failure = %v, want message \"gta infrastructure error\"", tc.Failure)
+	}
+	if tc := byName["v4.0.0"]; tc.Failure == nil || tc.Failure.Message != "go test" {
+		t.Errorf("v4.0.0 failure = %v, want message \"go test\"", tc.Failure)
+	}
+	if tc := byName["v1.0.0"]; tc.Failure != nil {
+		t.Errorf("v1.0.0 should not have failed, got %v", tc.Failure)
+	}
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// TextReporter writes human-readable, line-oriented output - the same shape
+// gta has always printed to the terminal.
+type TextReporter struct {
+	W io.Writer
+}
+
+func (r TextReporter) Report(dep string, attempts []Attempt) error {
+	for _, a := range attempts {
+		nv := fmt.Sprintf("%s@%s", dep, a.Version)
+		switch {
+		case a.Cached:
+			fmt.Fprintf(r.W, "%s already verified, skipping (use --force to recheck)\n", nv)
+		case a.SolveError != "":
+			fmt.Fprintf(r.W, "%s failed solving: %s\n", nv, a.SolveError)
+		case a.InfraError != "":
+			fmt.Fprintf(r.W, "%s could not be checked: %s\n", nv, a.InfraError)
+		case a.Command == "":
+			fmt.Fprintf(r.W, "%s solved successfully\n", nv)
+		case a.ExitCode != 0:
+			fmt.Fprintf(r.W, "%s failed: exit status %d\nstdout:\n%s\nstderr:\n%s\n", nv, a.ExitCode, a.Stdout, a.Stderr)
+		default:
+			fmt.Fprintf(r.W, "%s succeeded\n", nv)
+		}
+	}
+	return nil
+}
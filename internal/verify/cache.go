@@ -0,0 +1,163 @@
+// Package verify implements a digest-based cache of past gta runs, modeled
+// on gps/verify's vendor digest approach. It lets RunGTA skip re-running
+// checks for a (version, solver input) combination that already passed and
+// hasn't changed since. Solving is not skipped: it runs every time, and its
+// result is compared against the hash recorded with the earlier pass.
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Entry records the outcome of a previously verified (root project, dep@version,
+// solver inputs) combination, plus a hash of the vendor tree that solve
+// produced. The vendor hash lets a cache hit be invalidated when solving the
+// same inputs now yields a different tree - e.g. a branch or tag moved
+// upstream since the last run - even though the cache key itself didn't
+// change.
+type Entry struct {
+	Passed     bool   `json:"passed"`
+	VendorHash string `json:"vendorHash,omitempty"`
+}
+
+// Cache is a persisted map of cache key -> Entry.
+type Cache struct {
+	path    string
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Open loads the cache at path, returning an empty Cache if the file doesn't
+// yet exist.
+func Open(path string) (*Cache, error) {
+	c := &Cache{path: path, Entries: map[string]Entry{}}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Save writes the cache back to its path, creating parent directories as
+// needed.
+func (c *Cache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(c.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// Passed reports whether key has a recorded entry that passed, and - unless
+// vendorHash is empty - that vendorHash still matches the hash recorded for
+// that entry. A mismatch means the same (version, inputs) now solve to a
+// different vendor tree, so the earlier pass can no longer be trusted.
+func (c *Cache) Passed(key, vendorHash string) bool {
+	e, ok := c.Entries[key]
+	if !ok || !e.Passed {
+		return false
+	}
+	return vendorHash == "" || e.VendorHash == vendorHash
+}
+
+// Record stores the outcome of checking key.
+func (c *Cache) Record(key string, e Entry) {
+	c.Entries[key] = e
+}
+
+// DefaultPath returns $XDG_CACHE_HOME/gta/verified.json, falling back to
+// $HOME/.cache/gta/verified.json when XDG_CACHE_HOME is unset.
+func DefaultPath() string {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		base = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	return filepath.Join(base, "gta", "verified.json")
+}
+
+// Key combines the root project's content hash, the dependency@version
+// under test, and a hash of the solver inputs into a single cache key, so
+// any change to any of the three invalidates the cached entry.
+func Key(projectHash, depVersion, inputsHash string) string {
+	return HashStrings(projectHash, depVersion, inputsHash)
+}
+
+// HashStrings hashes ss into a hex digest, writing a NUL byte after each
+// string so that different splits of the same text hash differently.
+func HashStrings(ss ...string) string {
+	h := sha256.New()
+	for _, s := range ss {
+		io.WriteString(h, s)
+		io.WriteString(h, "\x00")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashTree hashes the relative paths and contents of every file under root,
+// skipping vendor/ and .git, so it reflects only the project's own source.
+func HashTree(root string) (string, error) {
+	var paths []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if rel == "vendor" || rel == ".git" {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, rel := range paths {
+		f, err := os.Open(filepath.Join(root, rel))
+		if err != nil {
+			return "", err
+		}
+		io.WriteString(h, rel)
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
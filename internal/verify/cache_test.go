@@ -0,0 +1,123 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashStringsDeterministicAndDistinct(t *testing.T) {
+	a := HashStrings("foo", "bar")
+	b := HashStrings("foo", "bar")
+	if a != b {
+		t.Errorf("HashStrings is not deterministic: %q != %q", a, b)
+	}
+
+	c := HashStrings("foo", "baz")
+	if a == c {
+		t.Errorf("HashStrings collided for different inputs: %q", a)
+	}
+
+	// ("foo", "bar") and ("foobar") must not collide just because the
+	// concatenation looks the same without the separator.
+	d := HashStrings("foobar")
+	if a == d {
+		t.Errorf("HashStrings did not separate its inputs: %q", a)
+	}
+}
+
+func TestHashTreeSkipsVendorAndGit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := HashTree(dir)
+	if err != nil {
+		t.Fatalf("HashTree: %s", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "vendor", "foo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "vendor", "foo", "foo.go"), []byte("package foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/master"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := HashTree(dir)
+	if err != nil {
+		t.Fatalf("HashTree: %s", err)
+	}
+
+	if before != after {
+		t.Errorf("HashTree changed after adding vendor/.git content: %q != %q", before, after)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := HashTree(dir)
+	if err != nil {
+		t.Fatalf("HashTree: %s", err)
+	}
+	if changed == after {
+		t.Errorf("HashTree did not change after editing a tracked file")
+	}
+}
+
+func TestCacheRecordSaveOpenRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "verified.json")
+
+	c, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	if c.Passed("some-key", "") {
+		t.Fatal("expected no entries in a freshly opened cache")
+	}
+
+	c.Record("some-key", Entry{Passed: true, VendorHash: "abc123"})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+
+	reloaded, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open after Save: %s", err)
+	}
+	if !reloaded.Passed("some-key", "abc123") {
+		t.Error("expected reloaded cache to report the recorded entry as passed")
+	}
+	if reloaded.Passed("some-key", "different-hash") {
+		t.Error("expected a mismatched vendor hash to invalidate the cached pass")
+	}
+	if reloaded.Passed("other-key", "") {
+		t.Error("expected no entry for an unrecorded key")
+	}
+}
+
+func TestCachePassedRequiresPassedEntry(t *testing.T) {
+	c := &Cache{Entries: map[string]Entry{
+		"failed-key": {Passed: false, VendorHash: "abc123"},
+	}}
+
+	if c.Passed("failed-key", "abc123") {
+		t.Error("expected a recorded failure not to count as passed, even with a matching vendor hash")
+	}
+}
+
+func TestOpenMissingFileReturnsEmptyCache(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	if len(c.Entries) != 0 {
+		t.Errorf("expected an empty cache, got %v", c.Entries)
+	}
+}